@@ -0,0 +1,118 @@
+package parser
+
+// Operator is a single PDF content-stream operator, as produced by
+// Parse. Concrete types are listed below; callers type-switch on the
+// value returned for each element of the []Operator slice.
+type Operator interface {
+	isOperator()
+}
+
+// BeginText corresponds to the BT operator.
+type BeginText struct{}
+
+// EndText corresponds to the ET operator.
+type EndText struct{}
+
+// ShowString corresponds to the Tj operator: show a single string.
+type ShowString struct {
+	Bytes []byte
+}
+
+// TJElem is one element of a TJ array operand: either a string to show
+// or a numeric position adjustment (in thousandths of a text-space
+// unit). Exactly one of Bytes/Adjust is meaningful, selected by
+// IsAdjust.
+type TJElem struct {
+	Bytes    []byte
+	Adjust   float64
+	IsAdjust bool
+}
+
+// ShowArray corresponds to the TJ operator: show a mix of strings and
+// position adjustments.
+type ShowArray struct {
+	Elems []TJElem
+}
+
+// SetTextMatrix corresponds to the Tm operator, which sets both the
+// text matrix and the text line matrix.
+type SetTextMatrix struct {
+	A, B, C, D, E, F float64
+}
+
+// SetCTM corresponds to the cm operator, which concatenates the given
+// matrix onto the current transformation matrix.
+type SetCTM struct {
+	A, B, C, D, E, F float64
+}
+
+// MoveText corresponds to the Td and TD operators: translate the text
+// line matrix by (Tx, Ty) and set the text matrix to the result. TD
+// additionally sets the leading to -Ty, reflected by SetLeading.
+type MoveText struct {
+	Tx, Ty     float64
+	SetLeading bool
+}
+
+// NextLine corresponds to the T* operator: move to the start of the
+// next line, using the current leading.
+type NextLine struct{}
+
+// SetFont corresponds to the Tf operator.
+type SetFont struct {
+	Name string
+	Size float64
+}
+
+// SetLeading corresponds to the TL operator.
+type SetLeading struct {
+	Leading float64
+}
+
+// SetCharSpace corresponds to the Tc operator.
+type SetCharSpace struct {
+	Spacing float64
+}
+
+// SetWordSpace corresponds to the Tw operator.
+type SetWordSpace struct {
+	Spacing float64
+}
+
+// SaveGraphicsState corresponds to the q operator.
+type SaveGraphicsState struct{}
+
+// RestoreGraphicsState corresponds to the Q operator.
+type RestoreGraphicsState struct{}
+
+// Rectangle corresponds to the re operator, which appends a rectangle
+// to the current path.
+type Rectangle struct {
+	X, Y, W, H float64
+}
+
+// ClipPath corresponds to the W operator, which marks the current path
+// for use as a clipping path once the path-painting operator runs.
+type ClipPath struct{}
+
+// EndPath corresponds to the n operator, which ends a path without
+// filling or stroking it (typically following W to install a clip).
+type EndPath struct{}
+
+func (BeginText) isOperator()            {}
+func (EndText) isOperator()              {}
+func (ShowString) isOperator()           {}
+func (ShowArray) isOperator()            {}
+func (SetTextMatrix) isOperator()        {}
+func (SetCTM) isOperator()               {}
+func (MoveText) isOperator()             {}
+func (NextLine) isOperator()             {}
+func (SetFont) isOperator()              {}
+func (SetLeading) isOperator()           {}
+func (SetCharSpace) isOperator()         {}
+func (SetWordSpace) isOperator()         {}
+func (SaveGraphicsState) isOperator()    {}
+func (RestoreGraphicsState) isOperator() {}
+func (Rectangle) isOperator()            {}
+func (ClipPath) isOperator()             {}
+func (EndPath) isOperator()              {}
@@ -0,0 +1,114 @@
+package parser
+
+import (
+	"math"
+
+	"github.com/HackJerseyCity/municourt/parser/font"
+)
+
+// PageData holds the raw content stream for a single page, along with
+// the fonts its /Resources /Font dictionary declares (see
+// font.LoadFonts), keyed by the resource name a Tf operator selects
+// them with (e.g. "F1"). Fonts may be nil, in which case Tj/TJ strings
+// are decoded as raw bytes.
+type PageData struct {
+	Content []byte
+	Fonts   map[string]*font.Font
+}
+
+// lineTolerance is how far apart, in page-space units, two text items'
+// line coordinates (see lineCoord) can be while still counting as the
+// same visual line.
+const lineTolerance = 2.0
+
+// tjSplitThreshold is the TJ adjustment magnitude, in thousandths of a
+// text-space unit, above which WalkContentStream treats the gap as a
+// genuine word or column break rather than ordinary kerning, and reports
+// the text before and after it as separate OnShowText calls.
+const tjSplitThreshold = 500.0
+
+// lineCoord returns the position along the axis perpendicular to the
+// matrix's reading direction (m.A, m.B) — the coordinate that stays
+// constant as glyphs are laid out along one line, regardless of page
+// rotation, nested q/Q, or clipping.
+func lineCoord(m Matrix) float64 {
+	norm := math.Hypot(m.A, m.B)
+	if norm == 0 {
+		return m.F
+	}
+	return (-m.B*m.E + m.A*m.F) / norm
+}
+
+// textItemCollector is the Handler ExtractTextItems drives
+// WalkContentStream with: it decodes each shown run through the current
+// font and groups runs into lines by their text rendering matrix's line
+// coordinate.
+type textItemCollector struct {
+	NopHandler
+	fonts     map[string]*font.Font
+	curFont   *font.Font
+	curTrm    Matrix
+	items     []string
+	haveLine  bool
+	lastCoord float64
+}
+
+func (c *textItemCollector) OnSetFont(name string, size float64) {
+	c.curFont = c.fonts[name]
+}
+
+func (c *textItemCollector) OnSetTextMatrix(m Matrix) {
+	c.curTrm = m
+}
+
+func (c *textItemCollector) OnShowText(bytes []byte, tx, ty float64) {
+	s := c.curFont.Decode(bytes)
+	coord := lineCoord(c.curTrm)
+	if c.haveLine && math.Abs(coord-c.lastCoord) > lineTolerance {
+		c.items = append(c.items, "")
+	}
+	c.items = append(c.items, s)
+	c.lastCoord = coord
+	c.haveLine = true
+}
+
+// ExtractTextItems parses the page's content stream and returns the
+// sequence of shown text fragments, in stream order. An empty string
+// marks a line break between the fragments before and after it.
+//
+// Each fragment's line coordinate is computed from its text rendering
+// matrix (Tm composed with the CTM), so grouping is correct even when
+// the content stream uses `cm`, nested `q`/`Q`, or splits a line's text
+// across separate BT/ET blocks (as PDF clipping paths often do). Each
+// fragment's bytes are decoded through the current font's /Encoding and
+// /ToUnicode CMap, selected by the most recent Tf; a page with no Fonts
+// resources decodes bytes as-is.
+func ExtractTextItems(page PageData) []string {
+	c := &textItemCollector{fonts: page.Fonts}
+	if err := WalkContentStream(page.Content, c); err != nil {
+		return nil
+	}
+	return c.items
+}
+
+// groupIntoLines splits a flat item slice, as returned by
+// ExtractTextItems, into lines using the "" break markers inserted
+// between visually distinct lines.
+func groupIntoLines(items []string) [][]string {
+	var lines [][]string
+	var cur []string
+	for _, s := range items {
+		if s == "" {
+			if len(cur) > 0 {
+				lines = append(lines, cur)
+				cur = nil
+			}
+			continue
+		}
+		cur = append(cur, s)
+	}
+	if len(cur) > 0 {
+		lines = append(lines, cur)
+	}
+	return lines
+}
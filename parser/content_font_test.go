@@ -0,0 +1,60 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/HackJerseyCity/municourt/parser/font"
+)
+
+func TestExtractTextItems_DecodesThroughFontEncoding(t *testing.T) {
+	// Code 0xAE is remapped via /Differences to the "fi" ligature glyph,
+	// as WinAnsi-based fonts commonly do for ligature-heavy text.
+	stream := []byte("BT\n/F1 10 Tf\n(o\xAEled)Tj\nET")
+
+	page := PageData{
+		Content: stream,
+		Fonts: map[string]*font.Font{
+			"F1": {Encoding: font.NewEncoding("WinAnsiEncoding", map[byte]string{0xAE: "fi"})},
+		},
+	}
+
+	items := ExtractTextItems(page)
+	var nonEmpty []string
+	for _, s := range items {
+		if s != "" {
+			nonEmpty = append(nonEmpty, s)
+		}
+	}
+
+	if len(nonEmpty) != 1 || nonEmpty[0] != "ofiled" {
+		t.Fatalf("expected [%q], got %v", "ofiled", nonEmpty)
+	}
+}
+
+func TestExtractTextItems_CIDFontDecodesToUnicode(t *testing.T) {
+	cmap := font.ParseCMap([]byte(`
+		1 beginbfrange
+		<0003> <0005> <0041>
+		endbfrange
+	`))
+	stream := []byte("BT\n/F1 10 Tf\n<000300040005>Tj\nET")
+
+	page := PageData{
+		Content: stream,
+		Fonts: map[string]*font.Font{
+			"F1": {CID: true, ToUnicode: cmap},
+		},
+	}
+
+	items := ExtractTextItems(page)
+	var nonEmpty []string
+	for _, s := range items {
+		if s != "" {
+			nonEmpty = append(nonEmpty, s)
+		}
+	}
+
+	if len(nonEmpty) != 1 || nonEmpty[0] != "ABC" {
+		t.Fatalf("expected [%q], got %v", "ABC", nonEmpty)
+	}
+}
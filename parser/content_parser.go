@@ -0,0 +1,188 @@
+package parser
+
+// Parse turns a PDF content stream into a sequence of Operators. It
+// implements, by hand, the shape of grammar described in content.peg
+// (which is descriptive documentation, not a source file anything
+// generates from): operands (numbers, strings, names, arrays)
+// accumulate on a stack until an operator keyword is seen, at which
+// point the operator's arity determines how many operands it consumes.
+//
+// Parse is deliberately forgiving: an operator whose operands don't
+// type-check, or one this package doesn't model, is dropped rather than
+// treated as a fatal error, since malformed or unsupported operators
+// elsewhere in a stream shouldn't prevent extracting the text that
+// parses cleanly.
+func Parse(data []byte) ([]Operator, error) {
+	lx := newLexer(data)
+	var ops []Operator
+	var stack []interface{}
+
+	pop := func(n int) []interface{} {
+		if n > len(stack) {
+			n = len(stack)
+		}
+		args := append([]interface{}(nil), stack[len(stack)-n:]...)
+		stack = stack[:len(stack)-n]
+		return args
+	}
+	num := func(v interface{}) float64 {
+		f, _ := v.(float64)
+		return f
+	}
+	name := func(v interface{}) string {
+		n, _ := v.(nameObj)
+		return string(n)
+	}
+	bytes := func(v interface{}) ([]byte, bool) {
+		switch b := v.(type) {
+		case []byte:
+			return b, true
+		case hexObj:
+			return []byte(b), true
+		}
+		return nil, false
+	}
+
+	for {
+		tok, err := lx.next()
+		if err != nil {
+			return nil, err
+		}
+
+		switch tok.kind {
+		case tokEOF:
+			return ops, nil
+		case tokNumber:
+			stack = append(stack, tok.num)
+			continue
+		case tokName:
+			stack = append(stack, nameObj(tok.name))
+			continue
+		case tokString:
+			stack = append(stack, tok.str)
+			continue
+		case tokHexString:
+			stack = append(stack, hexObj(tok.str))
+			continue
+		case tokArrayStart:
+			arr, err := lx.readArray()
+			if err != nil {
+				return nil, err
+			}
+			stack = append(stack, arr)
+			continue
+		case tokDictStart:
+			if err := lx.skipDict(); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		switch tok.op {
+		case "BT":
+			ops = append(ops, BeginText{})
+			stack = stack[:0]
+		case "ET":
+			ops = append(ops, EndText{})
+			stack = stack[:0]
+		case "Tj":
+			if a := pop(1); len(a) == 1 {
+				if b, ok := bytes(a[0]); ok {
+					ops = append(ops, ShowString{Bytes: b})
+				}
+			}
+		case "TJ":
+			if a := pop(1); len(a) == 1 {
+				if arr, ok := a[0].([]interface{}); ok {
+					ops = append(ops, ShowArray{Elems: toTJElems(arr)})
+				}
+			}
+		case "Tm":
+			if a := pop(6); len(a) == 6 {
+				ops = append(ops, SetTextMatrix{num(a[0]), num(a[1]), num(a[2]), num(a[3]), num(a[4]), num(a[5])})
+			}
+		case "cm":
+			if a := pop(6); len(a) == 6 {
+				ops = append(ops, SetCTM{num(a[0]), num(a[1]), num(a[2]), num(a[3]), num(a[4]), num(a[5])})
+			}
+		case "Td":
+			if a := pop(2); len(a) == 2 {
+				ops = append(ops, MoveText{Tx: num(a[0]), Ty: num(a[1])})
+			}
+		case "TD":
+			if a := pop(2); len(a) == 2 {
+				ops = append(ops, MoveText{Tx: num(a[0]), Ty: num(a[1]), SetLeading: true})
+			}
+		case "T*":
+			ops = append(ops, NextLine{})
+		case "Tf":
+			if a := pop(2); len(a) == 2 {
+				ops = append(ops, SetFont{Name: name(a[0]), Size: num(a[1])})
+			}
+		case "TL":
+			if a := pop(1); len(a) == 1 {
+				ops = append(ops, SetLeading{Leading: num(a[0])})
+			}
+		case "Tc":
+			if a := pop(1); len(a) == 1 {
+				ops = append(ops, SetCharSpace{Spacing: num(a[0])})
+			}
+		case "Tw":
+			if a := pop(1); len(a) == 1 {
+				ops = append(ops, SetWordSpace{Spacing: num(a[0])})
+			}
+		case "'":
+			a := pop(1)
+			ops = append(ops, NextLine{})
+			if len(a) == 1 {
+				if b, ok := bytes(a[0]); ok {
+					ops = append(ops, ShowString{Bytes: b})
+				}
+			}
+		case "\"":
+			if a := pop(3); len(a) == 3 {
+				ops = append(ops, SetWordSpace{Spacing: num(a[0])})
+				ops = append(ops, SetCharSpace{Spacing: num(a[1])})
+				ops = append(ops, NextLine{})
+				if b, ok := bytes(a[2]); ok {
+					ops = append(ops, ShowString{Bytes: b})
+				}
+			}
+		case "q":
+			ops = append(ops, SaveGraphicsState{})
+		case "Q":
+			ops = append(ops, RestoreGraphicsState{})
+		case "re":
+			if a := pop(4); len(a) == 4 {
+				ops = append(ops, Rectangle{X: num(a[0]), Y: num(a[1]), W: num(a[2]), H: num(a[3])})
+			}
+		case "W":
+			ops = append(ops, ClipPath{})
+		case "n":
+			ops = append(ops, EndPath{})
+			stack = stack[:0]
+		default:
+			// Unrecognised operator (fill/stroke/color/marked-content,
+			// etc.): drop any pending operands so it doesn't corrupt
+			// the next operator we do understand.
+			stack = stack[:0]
+		}
+	}
+}
+
+// toTJElems converts a decoded TJ array operand into TJElems, dropping
+// any element that isn't a string or a number.
+func toTJElems(arr []interface{}) []TJElem {
+	elems := make([]TJElem, 0, len(arr))
+	for _, v := range arr {
+		switch t := v.(type) {
+		case float64:
+			elems = append(elems, TJElem{Adjust: t, IsAdjust: true})
+		case []byte:
+			elems = append(elems, TJElem{Bytes: t})
+		case hexObj:
+			elems = append(elems, TJElem{Bytes: []byte(t)})
+		}
+	}
+	return elems
+}
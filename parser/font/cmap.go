@@ -0,0 +1,184 @@
+package font
+
+import (
+	"encoding/hex"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+)
+
+// CMap is a minimal parser and lookup table for the bfchar/bfrange
+// sections of a PDF /ToUnicode CMap stream. It does not implement the
+// full PostScript CMap language, only the subset ToUnicode streams
+// actually use to map character codes to Unicode text.
+type CMap struct {
+	// CodeLength is the byte width of codes in this CMap, taken from
+	// the widest entry seen. Zero means unknown; callers decoding a
+	// Type0/CID font default to 2 in that case.
+	CodeLength int
+
+	single map[uint32]string
+	ranges []bfRange
+}
+
+type bfRange struct {
+	lo, hi uint32
+	// Exactly one of dst/array is set. dst is the destination for lo;
+	// codes above lo add their offset to dst's last rune. array gives
+	// an explicit destination string per code in [lo, hi].
+	dst   string
+	array []string
+}
+
+// Lookup returns the Unicode text mapped to code, if any.
+func (c *CMap) Lookup(code uint32) (string, bool) {
+	if c == nil {
+		return "", false
+	}
+	if s, ok := c.single[code]; ok {
+		return s, true
+	}
+	for _, r := range c.ranges {
+		if code < r.lo || code > r.hi {
+			continue
+		}
+		if r.array != nil {
+			idx := int(code - r.lo)
+			if idx < len(r.array) {
+				return r.array[idx], true
+			}
+			return "", false
+		}
+		runes := []rune(r.dst)
+		if len(runes) == 0 {
+			return "", false
+		}
+		runes[len(runes)-1] += rune(code - r.lo)
+		return string(runes), true
+	}
+	return "", false
+}
+
+// ParseCMap parses the bfchar/bfrange sections of a ToUnicode CMap
+// stream. Other CMap operators (codespacerange, usecmap, cidrange,
+// etc.) are ignored, since text extraction only needs the code ->
+// Unicode mapping.
+func ParseCMap(data []byte) *CMap {
+	c := &CMap{single: map[uint32]string{}}
+	toks := tokenizeCMap(data)
+
+	for i := 0; i < len(toks); i++ {
+		switch toks[i] {
+		case "beginbfchar":
+			i++
+			for i+1 < len(toks) && toks[i] != "endbfchar" {
+				c.noteWidth(toks[i])
+				c.single[hexToken(toks[i])] = hexStringToText(toks[i+1])
+				i += 2
+			}
+		case "beginbfrange":
+			i++
+			for i < len(toks) && toks[i] != "endbfrange" {
+				if i+2 >= len(toks) {
+					break
+				}
+				lo, hi := hexToken(toks[i]), hexToken(toks[i+1])
+				c.noteWidth(toks[i])
+
+				if toks[i+2] == "[" {
+					j := i + 3
+					var arr []string
+					for j < len(toks) && toks[j] != "]" {
+						arr = append(arr, hexStringToText(toks[j]))
+						j++
+					}
+					c.ranges = append(c.ranges, bfRange{lo: lo, hi: hi, array: arr})
+					i = j + 1
+					continue
+				}
+
+				c.ranges = append(c.ranges, bfRange{lo: lo, hi: hi, dst: hexStringToText(toks[i+2])})
+				i += 3
+			}
+		}
+	}
+
+	return c
+}
+
+func (c *CMap) noteWidth(tok string) {
+	tok = strings.Trim(tok, "<>")
+	if n := (len(tok) + 1) / 2; n > c.CodeLength {
+		c.CodeLength = n
+	}
+}
+
+func hexToken(tok string) uint32 {
+	v, _ := strconv.ParseUint(strings.Trim(tok, "<>"), 16, 32)
+	return uint32(v)
+}
+
+// hexStringToText decodes a CMap hex-string destination, which the PDF
+// spec defines as UTF-16BE code units, into text.
+func hexStringToText(tok string) string {
+	b, err := hex.DecodeString(strings.Trim(tok, "<>"))
+	if err != nil || len(b) == 0 {
+		return ""
+	}
+	if len(b)%2 == 1 {
+		b = append(b, 0)
+	}
+	units := make([]uint16, len(b)/2)
+	for i := range units {
+		units[i] = uint16(b[2*i])<<8 | uint16(b[2*i+1])
+	}
+	return string(utf16.Decode(units))
+}
+
+// tokenizeCMap splits a CMap stream into hex strings ("<...>"), array
+// brackets, and bare keywords, ignoring everything else (whitespace,
+// comments, and PostScript machinery this package doesn't interpret).
+func tokenizeCMap(data []byte) []string {
+	var toks []string
+	i := 0
+	for i < len(data) {
+		switch b := data[i]; {
+		case b == ' ' || b == '\t' || b == '\r' || b == '\n' || b == '\f':
+			i++
+		case b == '%':
+			for i < len(data) && data[i] != '\n' {
+				i++
+			}
+		case b == '<':
+			j := i + 1
+			for j < len(data) && data[j] != '>' {
+				j++
+			}
+			toks = append(toks, string(data[i:j+1]))
+			i = j + 1
+		case b == '[' || b == ']':
+			toks = append(toks, string(b))
+			i++
+		default:
+			j := i
+			for j < len(data) && !isCMapDelim(data[j]) {
+				j++
+			}
+			if j == i {
+				i++
+				continue
+			}
+			toks = append(toks, string(data[i:j]))
+			i = j
+		}
+	}
+	return toks
+}
+
+func isCMapDelim(b byte) bool {
+	switch b {
+	case ' ', '\t', '\r', '\n', '\f', '<', '>', '[', ']', '%':
+		return true
+	}
+	return false
+}
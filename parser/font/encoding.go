@@ -0,0 +1,104 @@
+// Package font decodes the byte strings PDF Tj/TJ operators show into
+// Unicode text, using a font's /Encoding (WinAnsiEncoding,
+// MacRomanEncoding, and /Differences overrides) and, when present, its
+// /ToUnicode CMap.
+package font
+
+// winAnsiHighBytes holds the WinAnsiEncoding code points that differ
+// from plain ASCII/Latin-1, for the byte range this package has
+// actually needed to decode in municipal-court PDFs.
+var winAnsiHighBytes = map[byte]string{
+	0x80: "€",
+	0x82: "‚",
+	0x83: "ƒ",
+	0x84: "„",
+	0x85: "…",
+	0x86: "†",
+	0x87: "‡",
+	0x88: "ˆ",
+	0x89: "‰",
+	0x8A: "Š",
+	0x8B: "‹",
+	0x8C: "Œ",
+	0x8E: "Ž",
+	0x91: "‘",
+	0x92: "’",
+	0x93: "“",
+	0x94: "”",
+	0x95: "•",
+	0x96: "–",
+	0x97: "—",
+	0x98: "˜",
+	0x99: "™",
+	0x9A: "š",
+	0x9B: "›",
+	0x9C: "œ",
+	0x9E: "ž",
+	0x9F: "Ÿ",
+}
+
+// macRomanHighBytes is the MacRomanEncoding equivalent of
+// winAnsiHighBytes, covering the accented letters municipal-court PDFs
+// actually use.
+var macRomanHighBytes = map[byte]string{
+	0x8E: "é",
+	0x8F: "è",
+	0x96: "ñ",
+	0xA5: "•",
+	0xD0: "–",
+	0xD1: "—",
+	0xD2: "“",
+	0xD3: "”",
+	0xD4: "‘",
+	0xD5: "’",
+}
+
+// GlyphNames maps the Adobe standard glyph names this package resolves
+// via /Differences arrays to the text they represent. Ligatures expand
+// to their component letters rather than a single ligature codepoint,
+// so extracted text stays plain and searchable.
+var GlyphNames = map[string]string{
+	"fi":            "fi",
+	"fl":            "fl",
+	"ff":            "ff",
+	"ffi":           "ffi",
+	"ffl":           "ffl",
+	"space":         " ",
+	"endash":        "–",
+	"emdash":        "—",
+	"quoteleft":     "‘",
+	"quoteright":    "’",
+	"quotedblleft":  "“",
+	"quotedblright": "”",
+	"bullet":        "•",
+}
+
+// NewEncoding builds a byte -> text table for the named base encoding
+// ("WinAnsiEncoding" or "MacRomanEncoding"; any other name, including
+// "", is treated as WinAnsiEncoding), then applies a /Differences array on top:
+// differences maps a byte code to the PDF glyph name assigned to it,
+// which is resolved via GlyphNames.
+func NewEncoding(base string, differences map[byte]string) map[byte]string {
+	enc := make(map[byte]string, 256)
+	for c := 0; c < 256; c++ {
+		enc[byte(c)] = string(rune(c))
+	}
+
+	high := winAnsiHighBytes
+	if base == "MacRomanEncoding" {
+		high = macRomanHighBytes
+	}
+	for c, s := range high {
+		enc[c] = s
+	}
+
+	for c, glyphName := range differences {
+		if s, ok := GlyphNames[glyphName]; ok {
+			enc[c] = s
+		} else {
+			enc[c] = glyphName
+		}
+	}
+
+	return enc
+}
@@ -0,0 +1,72 @@
+package font
+
+import "strings"
+
+// Font holds enough of a PDF font's /Encoding and /ToUnicode to decode
+// the byte strings shown by Tj/TJ operators into Unicode text.
+type Font struct {
+	// CID marks a Type0/composite font, whose strings are decoded as
+	// multi-byte codes rather than one code per byte.
+	CID bool
+
+	// Encoding maps single-byte codes to text, built from the font's
+	// base /Encoding plus any /Differences (see NewEncoding). Unused
+	// for CID fonts.
+	Encoding map[byte]string
+
+	// ToUnicode is consulted before Encoding when present, for both
+	// simple and CID fonts, since it is authoritative regardless of the
+	// font's built-in encoding.
+	ToUnicode *CMap
+}
+
+// Decode converts the raw bytes of a Tj/TJ string operand, as they
+// appear in the content stream, into the Unicode text they represent.
+// A nil Font decodes bytes as-is, so callers can use it as the default
+// "no font resources available" case.
+func (f *Font) Decode(b []byte) string {
+	if f == nil {
+		return string(b)
+	}
+	if f.CID {
+		return f.decodeCID(b)
+	}
+	return f.decodeSimple(b)
+}
+
+func (f *Font) decodeSimple(b []byte) string {
+	var sb strings.Builder
+	for _, c := range b {
+		if s, ok := f.ToUnicode.Lookup(uint32(c)); ok {
+			sb.WriteString(s)
+			continue
+		}
+		if s, ok := f.Encoding[c]; ok {
+			sb.WriteString(s)
+			continue
+		}
+		sb.WriteByte(c)
+	}
+	return sb.String()
+}
+
+func (f *Font) decodeCID(b []byte) string {
+	n := 2
+	if f.ToUnicode != nil && f.ToUnicode.CodeLength > 0 {
+		n = f.ToUnicode.CodeLength
+	}
+
+	var sb strings.Builder
+	for i := 0; i+n <= len(b); i += n {
+		var code uint32
+		for j := 0; j < n; j++ {
+			code = code<<8 | uint32(b[i+j])
+		}
+		if s, ok := f.ToUnicode.Lookup(code); ok {
+			sb.WriteString(s)
+			continue
+		}
+		sb.WriteRune(rune(code))
+	}
+	return sb.String()
+}
@@ -0,0 +1,75 @@
+package font
+
+import "testing"
+
+func TestDecodeSimple_DifferencesLigature(t *testing.T) {
+	// Code 0xAE is remapped via /Differences to the "fi" glyph, as a
+	// font with a custom encoding for ligatures would declare.
+	f := &Font{
+		Encoding: NewEncoding("WinAnsiEncoding", map[byte]string{0xAE: "fi"}),
+	}
+
+	got := f.Decode([]byte{'o', 0xAE, 'l', 'e', 'd'})
+	if want := "ofiled"; got != want {
+		t.Errorf("Decode() = %q, want %q", got, want)
+	}
+}
+
+func TestDecodeSimple_ToUnicodeOverridesEncoding(t *testing.T) {
+	cmap := ParseCMap([]byte(`
+		1 beginbfchar
+		<41> <0042>
+		endbfchar
+	`))
+	f := &Font{
+		Encoding:  NewEncoding("WinAnsiEncoding", nil),
+		ToUnicode: cmap,
+	}
+
+	// Code 0x41 is 'A' in WinAnsiEncoding, but the ToUnicode CMap maps
+	// it to 'B' and should win.
+	if got := f.Decode([]byte{0x41}); got != "B" {
+		t.Errorf("Decode() = %q, want %q", got, "B")
+	}
+}
+
+func TestDecodeCID_BfRange(t *testing.T) {
+	cmap := ParseCMap([]byte(`
+		1 begincodespacerange
+		<0000> <FFFF>
+		endcodespacerange
+		1 beginbfrange
+		<0003> <0005> <0041>
+		endbfrange
+	`))
+	f := &Font{CID: true, ToUnicode: cmap}
+
+	// CIDs 3, 4, 5 map to consecutive codepoints starting at U+0041 ('A').
+	got := f.Decode([]byte{0x00, 0x03, 0x00, 0x04, 0x00, 0x05})
+	if want := "ABC"; got != want {
+		t.Errorf("Decode() = %q, want %q", got, want)
+	}
+}
+
+func TestDecodeCID_BfRangeArray(t *testing.T) {
+	cmap := ParseCMap([]byte(`
+		1 beginbfrange
+		<0001> <0003> [ <0058> <0059> <005A> ]
+		endbfrange
+	`))
+	f := &Font{CID: true, ToUnicode: cmap}
+
+	got := f.Decode([]byte{0x00, 0x01, 0x00, 0x02, 0x00, 0x03})
+	if want := "XYZ"; got != want {
+		t.Errorf("Decode() = %q, want %q", got, want)
+	}
+}
+
+func TestDecodeCID_FallsBackToRawCodeWithoutMapping(t *testing.T) {
+	f := &Font{CID: true, ToUnicode: ParseCMap(nil)}
+
+	got := f.Decode([]byte{0x00, 0x41})
+	if want := "A"; got != want {
+		t.Errorf("Decode() = %q, want %q", got, want)
+	}
+}
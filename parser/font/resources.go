@@ -0,0 +1,295 @@
+package font
+
+import (
+	"bytes"
+	"strconv"
+)
+
+// LoadFonts parses a page's /Resources /Font dictionary — the literal
+// PDF object syntax between its outer << >> delimiters — into one
+// *Font per resource name (e.g. "F1"), ready to be looked up by a
+// content stream's Tf operator.
+//
+// Each font's /Encoding and /ToUnicode are read straight out of the
+// dictionary bytes passed in. An entry given only as an indirect
+// reference (e.g. "12 0 R", rather than resolved inline) decodes to
+// nil and is skipped, since resolving indirect objects requires the
+// document's cross-reference table, which lives outside this package;
+// callers that have one should resolve references before calling
+// LoadFonts.
+func LoadFonts(dict []byte) map[string]*Font {
+	p := &objParser{data: dict}
+	root, _ := p.parseDictAndStream()
+
+	fonts := make(map[string]*Font)
+	for name, v := range root {
+		if sub, ok := v.(map[string]interface{}); ok {
+			fonts[name] = fontFromDict(sub)
+		}
+	}
+	return fonts
+}
+
+func fontFromDict(d map[string]interface{}) *Font {
+	f := &Font{}
+	if subtype, _ := d["Subtype"].(string); subtype == "Type0" {
+		f.CID = true
+	}
+
+	switch enc := d["Encoding"].(type) {
+	case string:
+		f.Encoding = NewEncoding(enc, nil)
+	case map[string]interface{}:
+		base, _ := enc["BaseEncoding"].(string)
+		f.Encoding = NewEncoding(base, parseDifferences(enc["Differences"]))
+	default:
+		f.Encoding = NewEncoding("", nil)
+	}
+
+	if tu, ok := d["ToUnicode"].([]byte); ok {
+		f.ToUnicode = ParseCMap(tu)
+	}
+	return f
+}
+
+// parseDifferences reads a /Differences array: a byte code followed by
+// the run of glyph names assigned to consecutive codes starting there.
+func parseDifferences(v interface{}) map[byte]string {
+	arr, ok := v.([]interface{})
+	if !ok {
+		return nil
+	}
+	diffs := make(map[byte]string)
+	code := 0
+	for _, e := range arr {
+		switch t := e.(type) {
+		case float64:
+			code = int(t)
+		case string:
+			diffs[byte(code)] = t
+			code++
+		}
+	}
+	return diffs
+}
+
+// objParser is a minimal recursive-descent parser for the subset of
+// PDF object syntax — dicts, arrays, names, strings, numbers, and the
+// stream that can trail a dict — that appears in a /Resources /Font
+// dictionary. It does not resolve indirect references ("12 0 R");
+// see LoadFonts.
+type objParser struct {
+	data []byte
+	pos  int
+}
+
+func (p *objParser) skipWS() {
+	for p.pos < len(p.data) {
+		switch b := p.data[p.pos]; {
+		case b == ' ' || b == '\t' || b == '\r' || b == '\n' || b == '\f':
+			p.pos++
+		case b == '%':
+			for p.pos < len(p.data) && p.data[p.pos] != '\n' {
+				p.pos++
+			}
+		default:
+			return
+		}
+	}
+}
+
+func (p *objParser) parseValue() interface{} {
+	p.skipWS()
+	if p.pos >= len(p.data) {
+		return nil
+	}
+	switch p.data[p.pos] {
+	case '/':
+		return p.parseName()
+	case '(':
+		return p.parseLiteralString()
+	case '[':
+		return p.parseArray()
+	case '<':
+		if p.pos+1 < len(p.data) && p.data[p.pos+1] == '<' {
+			d, stream := p.parseDictAndStream()
+			if stream != nil {
+				return stream
+			}
+			return d
+		}
+		return p.parseHexString()
+	default:
+		return p.parseKeywordOrNumber()
+	}
+}
+
+func (p *objParser) parseName() string {
+	p.pos++ // skip '/'
+	start := p.pos
+	for p.pos < len(p.data) && !isObjDelim(p.data[p.pos]) {
+		p.pos++
+	}
+	return string(p.data[start:p.pos])
+}
+
+func (p *objParser) parseLiteralString() string {
+	p.pos++ // skip '('
+	var sb []byte
+	depth := 1
+	for p.pos < len(p.data) && depth > 0 {
+		b := p.data[p.pos]
+		switch b {
+		case '\\':
+			p.pos++
+			if p.pos < len(p.data) {
+				sb = append(sb, p.data[p.pos])
+				p.pos++
+			}
+			continue
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth == 0 {
+				p.pos++
+				return string(sb)
+			}
+		}
+		sb = append(sb, b)
+		p.pos++
+	}
+	return string(sb)
+}
+
+func (p *objParser) parseHexString() string {
+	p.pos++ // skip '<'
+	start := p.pos
+	for p.pos < len(p.data) && p.data[p.pos] != '>' {
+		p.pos++
+	}
+	s := string(p.data[start:p.pos])
+	if p.pos < len(p.data) {
+		p.pos++ // skip '>'
+	}
+	return s
+}
+
+func (p *objParser) parseArray() []interface{} {
+	p.pos++ // skip '['
+	var arr []interface{}
+	for {
+		p.skipWS()
+		if p.pos >= len(p.data) || p.data[p.pos] == ']' {
+			p.pos++
+			return arr
+		}
+		arr = append(arr, p.parseValue())
+	}
+}
+
+// parseDictAndStream parses a "<< ... >>" dict and, if it's
+// immediately followed by a "stream ... endstream" section, returns
+// the stream's raw bytes instead of the dict describing it — the
+// shape LoadFonts needs for a direct (non-indirect) /ToUnicode CMap.
+func (p *objParser) parseDictAndStream() (map[string]interface{}, []byte) {
+	p.skipWS()
+	if p.pos+1 >= len(p.data) || p.data[p.pos] != '<' || p.data[p.pos+1] != '<' {
+		return nil, nil
+	}
+	p.pos += 2
+
+	d := make(map[string]interface{})
+	for {
+		p.skipWS()
+		if p.pos+1 < len(p.data) && p.data[p.pos] == '>' && p.data[p.pos+1] == '>' {
+			p.pos += 2
+			break
+		}
+		if p.pos >= len(p.data) || p.data[p.pos] != '/' {
+			break
+		}
+		key := p.parseName()
+		d[key] = p.parseValue()
+	}
+
+	p.skipWS()
+	if !p.hasKeyword("stream") {
+		return d, nil
+	}
+	p.pos += len("stream")
+	if p.pos < len(p.data) && p.data[p.pos] == '\r' {
+		p.pos++
+	}
+	if p.pos < len(p.data) && p.data[p.pos] == '\n' {
+		p.pos++
+	}
+	start := p.pos
+	rel := bytes.Index(p.data[start:], []byte("endstream"))
+	if rel < 0 {
+		return d, nil
+	}
+	stream := p.data[start : start+rel]
+	p.pos = start + rel + len("endstream")
+	return d, stream
+}
+
+func (p *objParser) hasKeyword(kw string) bool {
+	return p.pos+len(kw) <= len(p.data) && string(p.data[p.pos:p.pos+len(kw)]) == kw
+}
+
+func (p *objParser) parseKeywordOrNumber() interface{} {
+	start := p.pos
+	for p.pos < len(p.data) && !isObjDelim(p.data[p.pos]) {
+		p.pos++
+	}
+	tok := string(p.data[start:p.pos])
+	switch tok {
+	case "true":
+		return true
+	case "false":
+		return false
+	case "null":
+		return nil
+	}
+
+	n, err := strconv.ParseFloat(tok, 64)
+	if err != nil {
+		return tok
+	}
+	if p.consumeReferenceTail() {
+		return nil // unresolved indirect reference; see LoadFonts doc
+	}
+	return n
+}
+
+// consumeReferenceTail consumes a trailing "<generation> R" if the
+// number just parsed was an object number introducing an indirect
+// reference like "12 0 R", leaving the parser positioned just past it.
+func (p *objParser) consumeReferenceTail() bool {
+	save := p.pos
+	p.skipWS()
+	start := p.pos
+	for p.pos < len(p.data) && p.data[p.pos] >= '0' && p.data[p.pos] <= '9' {
+		p.pos++
+	}
+	if p.pos == start {
+		p.pos = save
+		return false
+	}
+	p.skipWS()
+	if p.pos < len(p.data) && p.data[p.pos] == 'R' && (p.pos+1 >= len(p.data) || isObjDelim(p.data[p.pos+1])) {
+		p.pos++
+		return true
+	}
+	p.pos = save
+	return false
+}
+
+func isObjDelim(b byte) bool {
+	switch b {
+	case ' ', '\t', '\r', '\n', '\f', '(', ')', '<', '>', '[', ']', '{', '}', '/', '%':
+		return true
+	}
+	return false
+}
@@ -0,0 +1,48 @@
+package font
+
+import "testing"
+
+func TestLoadFonts_NameEncodingAndDifferences(t *testing.T) {
+	dict := []byte(`<<
+		/F1 << /Type /Font /Subtype /Type1 /Encoding /WinAnsiEncoding >>
+		/F2 << /Type /Font /Subtype /Type1 /Encoding << /BaseEncoding /MacRomanEncoding /Differences [65 /fi 66 /fl] >> >>
+	>>`)
+
+	fonts := LoadFonts(dict)
+	if len(fonts) != 2 {
+		t.Fatalf("expected 2 fonts, got %d: %v", len(fonts), fonts)
+	}
+
+	if got := fonts["F1"].Decode([]byte{0x80}); got != "€" {
+		t.Errorf("F1.Decode(0x80) = %q, want €", got)
+	}
+
+	f2 := fonts["F2"]
+	if got := f2.Decode([]byte{65, 66}); got != "fifl" {
+		t.Errorf("F2.Decode = %q, want %q", got, "fifl")
+	}
+}
+
+func TestLoadFonts_CIDAndDirectToUnicodeStream(t *testing.T) {
+	dict := []byte("<<\n" +
+		"/F1 << /Type /Font /Subtype /Type0 /ToUnicode 9 0 R >>\n" +
+		"/F2 << /Type /Font /Subtype /Type0 /ToUnicode << /Length 10 >> stream\n" +
+		"1 beginbfchar\n<0041> <0042>\nendbfchar\n" +
+		"endstream >>\n" +
+		">>")
+
+	fonts := LoadFonts(dict)
+
+	f1 := fonts["F1"]
+	if !f1.CID {
+		t.Errorf("F1.CID = false, want true")
+	}
+	if f1.ToUnicode != nil {
+		t.Errorf("F1.ToUnicode = %v, want nil (indirect /ToUnicode is left unresolved)", f1.ToUnicode)
+	}
+
+	f2 := fonts["F2"]
+	if got := f2.Decode([]byte{0x00, 0x41}); got != "B" {
+		t.Errorf("F2.Decode() = %q, want %q", got, "B")
+	}
+}
@@ -0,0 +1,43 @@
+package parser
+
+// GraphicsState is the subset of the PDF graphics state that affects
+// text positioning: the current transformation matrix, the text
+// object's own matrices, and the text-state parameters (font, leading,
+// character and word spacing). q/Q push and pop a full copy of it.
+type GraphicsState struct {
+	CTM       Matrix
+	Tm        Matrix
+	Tlm       Matrix
+	Font      string
+	FontSize  float64
+	Leading   float64
+	CharSpace float64
+	WordSpace float64
+}
+
+func newGraphicsState() GraphicsState {
+	return GraphicsState{CTM: Identity(), Tm: Identity(), Tlm: Identity()}
+}
+
+// gsStack is the q/Q graphics-state stack: cur is the state in effect,
+// saved holds the states q pushed and Q pops back off.
+type gsStack struct {
+	cur   GraphicsState
+	saved []GraphicsState
+}
+
+func newGSStack() *gsStack {
+	return &gsStack{cur: newGraphicsState()}
+}
+
+func (s *gsStack) push() {
+	s.saved = append(s.saved, s.cur)
+}
+
+func (s *gsStack) pop() {
+	if len(s.saved) == 0 {
+		return
+	}
+	s.cur = s.saved[len(s.saved)-1]
+	s.saved = s.saved[:len(s.saved)-1]
+}
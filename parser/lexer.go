@@ -0,0 +1,362 @@
+package parser
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// nameObj and hexObj distinguish decoded operand values that started
+// life as a PDF name (/Foo) or hex string (<AB01>) from plain literal
+// string bytes, so the operator dispatch in content_parser.go can tell
+// them apart.
+type nameObj string
+type hexObj []byte
+
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokNumber
+	tokName
+	tokString
+	tokHexString
+	tokArrayStart
+	tokDictStart
+	tokOperator
+)
+
+type token struct {
+	kind tokenKind
+	num  float64
+	str  []byte
+	name string
+	op   string
+}
+
+// lexer tokenizes a PDF content stream per the shape described in
+// content.peg (documentation only — nothing generates this lexer).
+type lexer struct {
+	data []byte
+	pos  int
+}
+
+func newLexer(data []byte) *lexer {
+	return &lexer{data: data}
+}
+
+func isWhitespace(b byte) bool {
+	switch b {
+	case ' ', '\t', '\r', '\n', '\f', 0:
+		return true
+	}
+	return false
+}
+
+func isDelimiter(b byte) bool {
+	switch b {
+	case '(', ')', '<', '>', '[', ']', '{', '}', '/', '%':
+		return true
+	}
+	return false
+}
+
+func isHexDigit(b byte) bool {
+	return (b >= '0' && b <= '9') || (b >= 'a' && b <= 'f') || (b >= 'A' && b <= 'F')
+}
+
+func hexVal(b byte) byte {
+	switch {
+	case b >= '0' && b <= '9':
+		return b - '0'
+	case b >= 'a' && b <= 'f':
+		return b - 'a' + 10
+	case b >= 'A' && b <= 'F':
+		return b - 'A' + 10
+	}
+	return 0
+}
+
+func (l *lexer) skipWhitespaceAndComments() {
+	for l.pos < len(l.data) {
+		b := l.data[l.pos]
+		if isWhitespace(b) {
+			l.pos++
+			continue
+		}
+		if b == '%' {
+			for l.pos < len(l.data) && l.data[l.pos] != '\n' && l.data[l.pos] != '\r' {
+				l.pos++
+			}
+			continue
+		}
+		break
+	}
+}
+
+func (l *lexer) next() (token, error) {
+	l.skipWhitespaceAndComments()
+	if l.pos >= len(l.data) {
+		return token{kind: tokEOF}, nil
+	}
+
+	b := l.data[l.pos]
+	switch {
+	case b == '(':
+		s, err := l.readLiteralString()
+		if err != nil {
+			return token{}, err
+		}
+		return token{kind: tokString, str: s}, nil
+	case b == '<':
+		if l.pos+1 < len(l.data) && l.data[l.pos+1] == '<' {
+			l.pos += 2
+			return token{kind: tokDictStart}, nil
+		}
+		s, err := l.readHexString()
+		if err != nil {
+			return token{}, err
+		}
+		return token{kind: tokHexString, str: s}, nil
+	case b == '/':
+		return token{kind: tokName, name: l.readName()}, nil
+	case b == '[':
+		l.pos++
+		return token{kind: tokArrayStart}, nil
+	case b == '+' || b == '-' || b == '.' || (b >= '0' && b <= '9'):
+		if n, ok := l.tryReadNumber(); ok {
+			return token{kind: tokNumber, num: n}, nil
+		}
+		return token{kind: tokOperator, op: l.readOperator()}, nil
+	default:
+		op := l.readOperator()
+		if op == "" {
+			// Stray delimiter (e.g. a lone ']' or '>') with no operand
+			// or operator meaning here; skip it and keep going.
+			l.pos++
+			return l.next()
+		}
+		return token{kind: tokOperator, op: op}, nil
+	}
+}
+
+func (l *lexer) tryReadNumber() (float64, bool) {
+	start := l.pos
+	pos := l.pos
+	if pos < len(l.data) && (l.data[pos] == '+' || l.data[pos] == '-') {
+		pos++
+	}
+	sawDigit := false
+	for pos < len(l.data) && l.data[pos] >= '0' && l.data[pos] <= '9' {
+		pos++
+		sawDigit = true
+	}
+	if pos < len(l.data) && l.data[pos] == '.' {
+		pos++
+		for pos < len(l.data) && l.data[pos] >= '0' && l.data[pos] <= '9' {
+			pos++
+			sawDigit = true
+		}
+	}
+	if !sawDigit {
+		return 0, false
+	}
+	f, err := strconv.ParseFloat(string(l.data[start:pos]), 64)
+	if err != nil {
+		return 0, false
+	}
+	l.pos = pos
+	return f, true
+}
+
+func (l *lexer) readOperator() string {
+	start := l.pos
+	for l.pos < len(l.data) {
+		b := l.data[l.pos]
+		if isWhitespace(b) || isDelimiter(b) {
+			break
+		}
+		l.pos++
+	}
+	return string(l.data[start:l.pos])
+}
+
+func (l *lexer) readName() string {
+	l.pos++ // skip '/'
+	var buf []byte
+	for l.pos < len(l.data) {
+		b := l.data[l.pos]
+		if isWhitespace(b) || isDelimiter(b) {
+			break
+		}
+		if b == '#' && l.pos+2 < len(l.data) && isHexDigit(l.data[l.pos+1]) && isHexDigit(l.data[l.pos+2]) {
+			buf = append(buf, hexVal(l.data[l.pos+1])<<4|hexVal(l.data[l.pos+2]))
+			l.pos += 3
+			continue
+		}
+		buf = append(buf, b)
+		l.pos++
+	}
+	return string(buf)
+}
+
+func (l *lexer) readLiteralString() ([]byte, error) {
+	l.pos++ // skip '('
+	var buf []byte
+	depth := 1
+	for l.pos < len(l.data) {
+		b := l.data[l.pos]
+		switch b {
+		case '\\':
+			l.pos++
+			if l.pos >= len(l.data) {
+				return buf, fmt.Errorf("parser: unterminated escape in literal string")
+			}
+			e := l.data[l.pos]
+			switch {
+			case e == 'n':
+				buf = append(buf, '\n')
+				l.pos++
+			case e == 'r':
+				buf = append(buf, '\r')
+				l.pos++
+			case e == 't':
+				buf = append(buf, '\t')
+				l.pos++
+			case e == 'b':
+				buf = append(buf, '\b')
+				l.pos++
+			case e == 'f':
+				buf = append(buf, '\f')
+				l.pos++
+			case e == '(' || e == ')' || e == '\\':
+				buf = append(buf, e)
+				l.pos++
+			case e == '\n':
+				l.pos++ // line continuation, no output
+			case e == '\r':
+				l.pos++
+				if l.pos < len(l.data) && l.data[l.pos] == '\n' {
+					l.pos++
+				}
+			case e >= '0' && e <= '7':
+				v, n := 0, 0
+				for n < 3 && l.pos < len(l.data) && l.data[l.pos] >= '0' && l.data[l.pos] <= '7' {
+					v = v*8 + int(l.data[l.pos]-'0')
+					l.pos++
+					n++
+				}
+				buf = append(buf, byte(v))
+			default:
+				buf = append(buf, e)
+				l.pos++
+			}
+		case '(':
+			depth++
+			buf = append(buf, b)
+			l.pos++
+		case ')':
+			depth--
+			l.pos++
+			if depth == 0 {
+				return buf, nil
+			}
+			buf = append(buf, b)
+		default:
+			buf = append(buf, b)
+			l.pos++
+		}
+	}
+	return buf, fmt.Errorf("parser: unterminated literal string")
+}
+
+func (l *lexer) readHexString() ([]byte, error) {
+	l.pos++ // skip '<'
+	var digits []byte
+	for l.pos < len(l.data) {
+		b := l.data[l.pos]
+		if b == '>' {
+			l.pos++
+			if len(digits)%2 == 1 {
+				digits = append(digits, '0')
+			}
+			out := make([]byte, len(digits)/2)
+			for i := range out {
+				out[i] = hexVal(digits[2*i])<<4 | hexVal(digits[2*i+1])
+			}
+			return out, nil
+		}
+		if isWhitespace(b) {
+			l.pos++
+			continue
+		}
+		digits = append(digits, b)
+		l.pos++
+	}
+	return nil, fmt.Errorf("parser: unterminated hex string")
+}
+
+// readArray consumes tokens up to the matching ']', assuming the
+// opening '[' has already been consumed. Elements are the decoded
+// operand values: float64, []byte, hexObj, nameObj, or a nested
+// []interface{} for a nested array.
+func (l *lexer) readArray() ([]interface{}, error) {
+	var elems []interface{}
+	for {
+		l.skipWhitespaceAndComments()
+		if l.pos >= len(l.data) {
+			return nil, fmt.Errorf("parser: unterminated array")
+		}
+		if l.data[l.pos] == ']' {
+			l.pos++
+			return elems, nil
+		}
+		tok, err := l.next()
+		if err != nil {
+			return nil, err
+		}
+		switch tok.kind {
+		case tokNumber:
+			elems = append(elems, tok.num)
+		case tokString:
+			elems = append(elems, tok.str)
+		case tokHexString:
+			elems = append(elems, hexObj(tok.str))
+		case tokName:
+			elems = append(elems, nameObj(tok.name))
+		case tokArrayStart:
+			sub, err := l.readArray()
+			if err != nil {
+				return nil, err
+			}
+			elems = append(elems, sub)
+		case tokEOF:
+			return nil, fmt.Errorf("parser: unterminated array")
+		default:
+			// Dicts and bare operators don't belong in an operand
+			// array; ignore rather than fail the whole stream.
+		}
+	}
+}
+
+// skipDict consumes tokens up to the matching '>>', assuming the
+// opening '<<' has already been consumed.
+func (l *lexer) skipDict() error {
+	depth := 1
+	for l.pos < len(l.data) {
+		if l.data[l.pos] == '<' && l.pos+1 < len(l.data) && l.data[l.pos+1] == '<' {
+			depth++
+			l.pos += 2
+			continue
+		}
+		if l.data[l.pos] == '>' && l.pos+1 < len(l.data) && l.data[l.pos+1] == '>' {
+			depth--
+			l.pos += 2
+			if depth == 0 {
+				return nil
+			}
+			continue
+		}
+		l.pos++
+	}
+	return fmt.Errorf("parser: unterminated dictionary")
+}
@@ -0,0 +1,43 @@
+package parser
+
+// Matrix is a PDF transformation matrix in the compact six-value form
+// content streams use:
+//
+//	[ a b 0 ]
+//	[ c d 0 ]
+//	[ e f 1 ]
+//
+// A point (x, y) is transformed as (x, y, 1) * Matrix.
+type Matrix struct {
+	A, B, C, D, E, F float64
+}
+
+// Identity returns the identity matrix.
+func Identity() Matrix {
+	return Matrix{A: 1, D: 1}
+}
+
+// Multiply returns the matrix that applies m first and then other,
+// matching the composition order the PDF spec uses for `cm` and for
+// combining a text matrix with the CTM.
+func (m Matrix) Multiply(other Matrix) Matrix {
+	return Matrix{
+		A: m.A*other.A + m.B*other.C,
+		B: m.A*other.B + m.B*other.D,
+		C: m.C*other.A + m.D*other.C,
+		D: m.C*other.B + m.D*other.D,
+		E: m.E*other.A + m.F*other.C + other.E,
+		F: m.E*other.B + m.F*other.D + other.F,
+	}
+}
+
+// Apply transforms the point (x, y) by m.
+func (m Matrix) Apply(x, y float64) (float64, float64) {
+	return x*m.A + y*m.C + m.E, x*m.B + y*m.D + m.F
+}
+
+// Translate returns the matrix that translates by (tx, ty) and then
+// applies m, as used by the Td/TD text line matrix update.
+func Translate(tx, ty float64, m Matrix) Matrix {
+	return Matrix{A: 1, D: 1, E: tx, F: ty}.Multiply(m)
+}
@@ -0,0 +1,331 @@
+package parser
+
+import (
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/HackJerseyCity/municourt/parser/font"
+)
+
+// Row is one reconstructed row of a table extracted from a page's
+// content stream: one cell per column, in column order.
+type Row struct {
+	Cells  []string
+	Header bool
+}
+
+// rowTolerance is how far apart, in page-space units, two text items'
+// row coordinates can be while still counting as the same table row.
+const rowTolerance = 3.0
+
+// glyphWidthEm is the assumed width of a glyph, as a fraction of the
+// text matrix's own scale, for fonts whose /Widths this package doesn't
+// have access to. It's only used to estimate how far text advances, so
+// that fragments without their own explicit Tm/Td (as within one TJ
+// array, or a glyph clipped into its own BT/ET block) still get
+// distinct positions to cluster into columns.
+const glyphWidthEm = 0.5
+
+// columnTolerance is how many times the page's median glyph width two
+// column centers may differ by and still merge into one column, when
+// clustering column boundaries.
+const columnTolerance = 6.0
+
+// positionedItem is a text fragment together with the page-space
+// position and reading direction of the text matrix that placed it,
+// used only for table reconstruction.
+type positionedItem struct {
+	text  string
+	x, y  float64
+	a, b  float64 // reading-direction vector of the matrix that placed it
+	width float64 // page-space extent along (a, b)
+}
+
+// rowCoord is this item's position along the axis perpendicular to its
+// reading direction (see lineCoord); items on the same visual row share
+// a rowCoord regardless of page rotation.
+func (it positionedItem) rowCoord() float64 {
+	norm := math.Hypot(it.a, it.b)
+	if norm == 0 {
+		return it.y
+	}
+	return (-it.b*it.x + it.a*it.y) / norm
+}
+
+// colCoord is this item's position along its reading direction.
+func (it positionedItem) colCoord() float64 {
+	norm := math.Hypot(it.a, it.b)
+	if norm == 0 {
+		return it.x
+	}
+	return (it.a*it.x + it.b*it.y) / norm
+}
+
+// extractPositionedItems runs the same content-stream interpretation as
+// ExtractTextItems, but records each fragment's page-space position and
+// reading direction instead of just its text, advancing an estimated
+// pen position across Tj/TJ calls so that fragments sharing a Tm (as
+// within one TJ array) still get distinct positions.
+func extractPositionedItems(page PageData) []positionedItem {
+	ops, err := Parse(page.Content)
+	if err != nil {
+		return nil
+	}
+
+	gs := newGSStack()
+	var curFont *font.Font
+	var items []positionedItem
+
+	// runX, runY, runA, runB describe the current text position and
+	// reading direction; they're recomputed whenever an operator sets a
+	// new Tm, and advanced as text is shown.
+	var runX, runY, runA, runB float64
+	recomputeRun := func() {
+		trm := gs.cur.Tm.Multiply(gs.cur.CTM)
+		runX, runY = trm.Apply(0, 0)
+		runA, runB = trm.A, trm.B
+	}
+	recomputeRun()
+
+	// advance moves the run position forward along its reading
+	// direction by a text-space distance, emitting an item at the
+	// pre-advance position when text is non-empty.
+	advance := func(text string, textSpaceDelta float64) {
+		scale := math.Hypot(runA, runB)
+		delta := textSpaceDelta * scale
+		if text != "" {
+			items = append(items, positionedItem{text: text, x: runX, y: runY, a: runA, b: runB, width: delta})
+		}
+		norm := scale
+		if norm == 0 {
+			norm = 1
+		}
+		runX += delta * runA / norm
+		runY += delta * runB / norm
+	}
+
+	for _, op := range ops {
+		switch o := op.(type) {
+		case BeginText:
+			gs.cur.Tm = Identity()
+			gs.cur.Tlm = Identity()
+			recomputeRun()
+		case SetCTM:
+			gs.cur.CTM = Matrix{o.A, o.B, o.C, o.D, o.E, o.F}.Multiply(gs.cur.CTM)
+		case SetTextMatrix:
+			gs.cur.Tm = Matrix{o.A, o.B, o.C, o.D, o.E, o.F}
+			gs.cur.Tlm = gs.cur.Tm
+			recomputeRun()
+		case MoveText:
+			gs.cur.Tlm = Translate(o.Tx, o.Ty, gs.cur.Tlm)
+			gs.cur.Tm = gs.cur.Tlm
+			if o.SetLeading {
+				gs.cur.Leading = -o.Ty
+			}
+			recomputeRun()
+		case NextLine:
+			gs.cur.Tlm = Translate(0, -gs.cur.Leading, gs.cur.Tlm)
+			gs.cur.Tm = gs.cur.Tlm
+			recomputeRun()
+		case SetFont:
+			gs.cur.Font = o.Name
+			gs.cur.FontSize = o.Size
+			curFont = page.Fonts[o.Name]
+		case SetLeading:
+			gs.cur.Leading = o.Leading
+		case SetCharSpace:
+			gs.cur.CharSpace = o.Spacing
+		case SetWordSpace:
+			gs.cur.WordSpace = o.Spacing
+		case SaveGraphicsState:
+			gs.push()
+		case RestoreGraphicsState:
+			gs.pop()
+			recomputeRun()
+		case ShowString:
+			text := curFont.Decode(o.Bytes)
+			advance(text, float64(len([]rune(text)))*glyphWidthEm)
+		case ShowArray:
+			var buf []byte
+			flush := func() {
+				if len(buf) == 0 {
+					return
+				}
+				text := curFont.Decode(buf)
+				advance(text, float64(len([]rune(text)))*glyphWidthEm)
+				buf = nil
+			}
+			for _, e := range o.Elems {
+				if e.IsAdjust {
+					flush()
+					advance("", -(e.Adjust / 1000.0))
+					continue
+				}
+				buf = append(buf, e.Bytes...)
+			}
+			flush()
+		}
+	}
+
+	return items
+}
+
+// ExtractTable reconstructs the page's text as a 2-D grid: text items
+// are clustered into rows by their row coordinate and into columns by
+// their column coordinate, fragments landing in the same cell are
+// concatenated in reading order, and all-caps rows spanning multiple
+// columns are marked as headers.
+func ExtractTable(page PageData) []Row {
+	items := extractPositionedItems(page)
+	if len(items) == 0 {
+		return nil
+	}
+
+	itemRows := clusterRows(items)
+	columns := clusterColumns(items)
+
+	rows := make([]Row, 0, len(itemRows))
+	for _, line := range itemRows {
+		cells := make([]string, len(columns))
+		for _, it := range line {
+			col := nearestColumn(columns, it.colCoord()+it.width/2)
+			cells[col] += it.text
+		}
+		rows = append(rows, Row{Cells: cells, Header: isHeaderRow(cells)})
+	}
+	return rows
+}
+
+// clusterRows groups items whose row coordinates fall within
+// rowTolerance of each other, in row order (top of the page first), and
+// sorts each row's items into reading order.
+func clusterRows(items []positionedItem) [][]positionedItem {
+	sorted := append([]positionedItem(nil), items...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].rowCoord() > sorted[j].rowCoord() })
+
+	var rows [][]positionedItem
+	var cur []positionedItem
+	var lastRow float64
+	for i, it := range sorted {
+		if i > 0 && math.Abs(it.rowCoord()-lastRow) > rowTolerance {
+			rows = append(rows, cur)
+			cur = nil
+		}
+		cur = append(cur, it)
+		lastRow = it.rowCoord()
+	}
+	if len(cur) > 0 {
+		rows = append(rows, cur)
+	}
+
+	for _, row := range rows {
+		sort.SliceStable(row, func(i, j int) bool { return row[i].colCoord() < row[j].colCoord() })
+	}
+	return rows
+}
+
+// clusterColumns performs 1-D agglomerative clustering of every item's
+// column-coordinate center across the whole page, with a tolerance
+// derived from the median glyph width, and returns the resulting column
+// boundaries as their mean center, in column order.
+func clusterColumns(items []positionedItem) []float64 {
+	centers := make([]float64, len(items))
+	var glyphWidths []float64
+	for i, it := range items {
+		centers[i] = it.colCoord() + it.width/2
+		if n := len([]rune(it.text)); n > 0 && it.width > 0 {
+			glyphWidths = append(glyphWidths, it.width/float64(n))
+		}
+	}
+	sort.Float64s(centers)
+
+	tolerance := median(glyphWidths) * columnTolerance
+	if tolerance <= 0 {
+		tolerance = 5
+	}
+
+	var columns []float64
+	var sum float64
+	var n int
+	flush := func() {
+		if n > 0 {
+			columns = append(columns, sum/float64(n))
+		}
+		sum, n = 0, 0
+	}
+	var last float64
+	for i, c := range centers {
+		if i > 0 && c-last > tolerance {
+			flush()
+		}
+		sum += c
+		n++
+		last = c
+	}
+	flush()
+	return columns
+}
+
+func median(xs []float64) float64 {
+	if len(xs) == 0 {
+		return 0
+	}
+	sorted := append([]float64(nil), xs...)
+	sort.Float64s(sorted)
+	return sorted[len(sorted)/2]
+}
+
+func nearestColumn(columns []float64, x float64) int {
+	best, bestDist := 0, math.MaxFloat64
+	for i, c := range columns {
+		if d := math.Abs(x - c); d < bestDist {
+			best, bestDist = i, d
+		}
+	}
+	return best
+}
+
+// isHeaderRow reports whether cells looks like a table header: at
+// least two cells containing an upper-case letter, and none containing
+// a lower-case one. A cell with no letters at all (digits, punctuation)
+// doesn't count as evidence either way, so a data row like
+// "ABSECON / 12 / 5" isn't misread as a header just because its
+// municipality name happens to be all-caps.
+func isHeaderRow(cells []string) bool {
+	upperCells := 0
+	for _, c := range cells {
+		if c == "" || (c == strings.ToUpper(c) && c == strings.ToLower(c)) {
+			continue // empty, or no letters at all (digits/punctuation)
+		}
+		if c != strings.ToUpper(c) {
+			return false
+		}
+		upperCells++
+	}
+	return upperCells >= 2
+}
+
+// RepeatHeaders propagates the most recently seen header row into any
+// later page's table that doesn't start with one of its own, so a table
+// that spans a page break keeps its column headers.
+func RepeatHeaders(pages [][]Row) [][]Row {
+	out := make([][]Row, len(pages))
+	var lastHeader []string
+	for i, rows := range pages {
+		if len(rows) > 0 && rows[0].Header {
+			lastHeader = rows[0].Cells
+			out[i] = rows
+			continue
+		}
+		if lastHeader == nil {
+			out[i] = rows
+			continue
+		}
+		merged := make([]Row, 0, len(rows)+1)
+		merged = append(merged, Row{Cells: append([]string(nil), lastHeader...), Header: true})
+		merged = append(merged, rows...)
+		out[i] = merged
+	}
+	return out
+}
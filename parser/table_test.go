@@ -0,0 +1,163 @@
+package parser
+
+import "testing"
+
+func TestExtractTable_ClippedGlyphCollapsesIntoOneCell(t *testing.T) {
+	// "UNIONCIT" and "Y" are shown in separate BT/ET blocks at slightly
+	// different positions, as a clipped glyph would be, but sit on the
+	// same visual (rotated) line and should collapse into one cell.
+	stream := []byte(`BT
+0 10 -10 0 50 500 Tm
+(UNIONCIT)Tj
+ET
+BT
+0 10 -10 0 50 545 Tm
+(Y)Tj
+ET
+BT
+0 10 -10 0 80 500 Tm
+(NEXTROW)Tj
+ET`)
+
+	rows := ExtractTable(PageData{Content: stream})
+	if len(rows) != 2 {
+		t.Fatalf("expected 2 rows, got %d: %v", len(rows), rows)
+	}
+	if got := rows[0].Cells; len(got) != 1 || got[0] != "UNIONCITY" {
+		t.Errorf("row 0 = %v, want [\"UNIONCITY\"]", got)
+	}
+	if got := rows[1].Cells; len(got) != 1 || got[0] != "NEXTROW" {
+		t.Errorf("row 1 = %v, want [\"NEXTROW\"]", got)
+	}
+}
+
+func TestExtractTable_HeaderRowAndColumns(t *testing.T) {
+	stream := []byte(`BT
+1 0 0 1 100 700 Tm
+(NAME)Tj
+ET
+BT
+1 0 0 1 250 700 Tm
+(STATUS)Tj
+ET
+BT
+1 0 0 1 400 700 Tm
+(COUNT)Tj
+ET
+BT
+1 0 0 1 100 685 Tm
+(Smith)Tj
+ET
+BT
+1 0 0 1 250 685 Tm
+(Open)Tj
+ET
+BT
+1 0 0 1 400 685 Tm
+(3)Tj
+ET`)
+
+	rows := ExtractTable(PageData{Content: stream})
+	if len(rows) != 2 {
+		t.Fatalf("expected 2 rows, got %d: %v", len(rows), rows)
+	}
+
+	header := rows[0]
+	if !header.Header {
+		t.Errorf("expected row 0 to be detected as a header, got %+v", header)
+	}
+	if want := []string{"NAME", "STATUS", "COUNT"}; !equalStrings(header.Cells, want) {
+		t.Errorf("header cells = %v, want %v", header.Cells, want)
+	}
+
+	data := rows[1]
+	if data.Header {
+		t.Errorf("expected row 1 to not be a header, got %+v", data)
+	}
+	if want := []string{"Smith", "Open", "3"}; !equalStrings(data.Cells, want) {
+		t.Errorf("data cells = %v, want %v", data.Cells, want)
+	}
+}
+
+func TestExtractTable_AllCapsMunicipalityDoesNotLookLikeHeader(t *testing.T) {
+	// A single all-caps municipality name alongside plain numeric cells
+	// is an ordinary data row in these fixtures, not a header: only one
+	// of its cells actually contains letters.
+	stream := []byte(`BT
+1 0 0 1 100 700 Tm
+(ABSECON)Tj
+ET
+BT
+1 0 0 1 250 700 Tm
+(12)Tj
+ET
+BT
+1 0 0 1 400 700 Tm
+(5)Tj
+ET`)
+
+	rows := ExtractTable(PageData{Content: stream})
+	if len(rows) != 1 {
+		t.Fatalf("expected 1 row, got %d: %v", len(rows), rows)
+	}
+	if rows[0].Header {
+		t.Errorf("expected ABSECON/12/5 to not be detected as a header, got %+v", rows[0])
+	}
+}
+
+func TestExtractTable_WideItemAssignedByCenterNotLeftEdge(t *testing.T) {
+	// The "AMT" column is centered at x=200. A data-row item sitting far
+	// to its left but wide enough that its center also lands at x=200
+	// must join that column, even though its left edge (x=105) sits
+	// right next to the "NAME" column at x=100.
+	stream := []byte(`BT
+1 0 0 1 100 700 Tm
+(NAME)Tj
+ET
+BT
+1 0 0 1 200 700 Tm
+(AMT)Tj
+ET
+BT
+190 0 0 190 105 680 Tm
+(CC)Tj
+ET`)
+
+	rows := ExtractTable(PageData{Content: stream})
+	if len(rows) != 2 {
+		t.Fatalf("expected 2 rows, got %d: %v", len(rows), rows)
+	}
+	if got := rows[1].Cells; len(got) != 2 || got[0] != "" || got[1] != "CC" {
+		t.Errorf("data cells = %v, want [\"\", \"CC\"]", got)
+	}
+}
+
+func TestRepeatHeaders_PropagatesAcrossPageSplit(t *testing.T) {
+	header := Row{Cells: []string{"NAME", "STATUS", "COUNT"}, Header: true}
+	page1 := []Row{header, {Cells: []string{"Smith", "Open", "3"}}}
+	page2 := []Row{{Cells: []string{"Jones", "Closed", "1"}}} // no header of its own
+
+	merged := RepeatHeaders([][]Row{page1, page2})
+
+	if len(merged) != 2 {
+		t.Fatalf("expected 2 pages, got %d", len(merged))
+	}
+	if len(merged[1]) != 2 || !merged[1][0].Header {
+		t.Fatalf("expected page 2 to start with a repeated header, got %v", merged[1])
+	}
+	if !equalStrings(merged[1][0].Cells, header.Cells) {
+		t.Errorf("repeated header = %v, want %v", merged[1][0].Cells, header.Cells)
+	}
+}
+
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
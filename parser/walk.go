@@ -0,0 +1,193 @@
+package parser
+
+import "math"
+
+// Rect is an axis-aligned rectangle in the units of whichever matrix was
+// current when it was defined (see the re/W operators), as reported by
+// OnClip.
+type Rect struct {
+	X, Y, W, H float64
+}
+
+// Handler receives WalkContentStream's callbacks, in stream order. Not
+// every interpreted operator has a callback of its own: TL, Tc, Tw, re
+// and n only update the walker's state. Implementations
+// that don't care about a given operator can embed NopHandler instead of
+// writing out every method.
+type Handler interface {
+	OnBeginText()
+	OnEndText()
+	OnShowText(bytes []byte, tx, ty float64)
+	OnSetTextMatrix(m Matrix)
+	OnSetFont(name string, size float64)
+	OnSaveState()
+	OnRestoreState()
+	OnClip(rect Rect)
+}
+
+// NopHandler implements Handler with no-op methods, so a Handler that
+// only cares about one or two callbacks can embed it instead of stubbing
+// out the rest.
+type NopHandler struct{}
+
+func (NopHandler) OnBeginText()                            {}
+func (NopHandler) OnEndText()                              {}
+func (NopHandler) OnShowText(bytes []byte, tx, ty float64) {}
+func (NopHandler) OnSetTextMatrix(m Matrix)                {}
+func (NopHandler) OnSetFont(name string, size float64)     {}
+func (NopHandler) OnSaveState()                            {}
+func (NopHandler) OnRestoreState()                         {}
+func (NopHandler) OnClip(rect Rect)                        {}
+
+// MultiHandler fans a content stream's callbacks out to several Handlers,
+// in order, so more than one consumer (e.g. an ExtractTextItems-style
+// text collector and a debug visualizer) can walk a stream in a single
+// pass.
+type MultiHandler []Handler
+
+func (m MultiHandler) OnBeginText() {
+	for _, h := range m {
+		h.OnBeginText()
+	}
+}
+
+func (m MultiHandler) OnEndText() {
+	for _, h := range m {
+		h.OnEndText()
+	}
+}
+
+func (m MultiHandler) OnShowText(bytes []byte, tx, ty float64) {
+	for _, h := range m {
+		h.OnShowText(bytes, tx, ty)
+	}
+}
+
+func (m MultiHandler) OnSetTextMatrix(mat Matrix) {
+	for _, h := range m {
+		h.OnSetTextMatrix(mat)
+	}
+}
+
+func (m MultiHandler) OnSetFont(name string, size float64) {
+	for _, h := range m {
+		h.OnSetFont(name, size)
+	}
+}
+
+func (m MultiHandler) OnSaveState() {
+	for _, h := range m {
+		h.OnSaveState()
+	}
+}
+
+func (m MultiHandler) OnRestoreState() {
+	for _, h := range m {
+		h.OnRestoreState()
+	}
+}
+
+func (m MultiHandler) OnClip(rect Rect) {
+	for _, h := range m {
+		h.OnClip(rect)
+	}
+}
+
+// WalkContentStream parses data as a PDF content stream and drives h
+// through its operators in order, maintaining the graphics-state stack
+// (CTM, text matrix, text line matrix, leading) needed to report text
+// matrices and show positions in page space; OnClip's rectangle is
+// passed through untransformed. Show operators are reported once per
+// contiguous run of glyphs: a TJ array is split into separate
+// OnShowText calls wherever an adjustment's magnitude exceeds
+// tjSplitThreshold, the same rule ExtractTextItems uses to tell a
+// deliberate word/column gap from ordinary kerning.
+func WalkContentStream(data []byte, h Handler) error {
+	ops, err := Parse(data)
+	if err != nil {
+		return err
+	}
+
+	gs := newGSStack()
+	var lastRect Rect
+
+	notifyTm := func() {
+		h.OnSetTextMatrix(gs.cur.Tm.Multiply(gs.cur.CTM))
+	}
+	showRun := func(buf []byte) {
+		if len(buf) == 0 {
+			return
+		}
+		trm := gs.cur.Tm.Multiply(gs.cur.CTM)
+		x, y := trm.Apply(0, 0)
+		h.OnShowText(buf, x, y)
+	}
+
+	for _, op := range ops {
+		switch o := op.(type) {
+		case BeginText:
+			gs.cur.Tm = Identity()
+			gs.cur.Tlm = Identity()
+			h.OnBeginText()
+			notifyTm()
+		case EndText:
+			h.OnEndText()
+		case SetCTM:
+			gs.cur.CTM = Matrix{o.A, o.B, o.C, o.D, o.E, o.F}.Multiply(gs.cur.CTM)
+			notifyTm()
+		case SetTextMatrix:
+			gs.cur.Tm = Matrix{o.A, o.B, o.C, o.D, o.E, o.F}
+			gs.cur.Tlm = gs.cur.Tm
+			notifyTm()
+		case MoveText:
+			gs.cur.Tlm = Translate(o.Tx, o.Ty, gs.cur.Tlm)
+			gs.cur.Tm = gs.cur.Tlm
+			if o.SetLeading {
+				gs.cur.Leading = -o.Ty
+			}
+			notifyTm()
+		case NextLine:
+			gs.cur.Tlm = Translate(0, -gs.cur.Leading, gs.cur.Tlm)
+			gs.cur.Tm = gs.cur.Tlm
+			notifyTm()
+		case SetFont:
+			gs.cur.Font = o.Name
+			gs.cur.FontSize = o.Size
+			h.OnSetFont(o.Name, o.Size)
+		case SetLeading:
+			gs.cur.Leading = o.Leading
+		case SetCharSpace:
+			gs.cur.CharSpace = o.Spacing
+		case SetWordSpace:
+			gs.cur.WordSpace = o.Spacing
+		case SaveGraphicsState:
+			gs.push()
+			h.OnSaveState()
+		case RestoreGraphicsState:
+			gs.pop()
+			h.OnRestoreState()
+			notifyTm()
+		case Rectangle:
+			lastRect = Rect{X: o.X, Y: o.Y, W: o.W, H: o.H}
+		case ClipPath:
+			h.OnClip(lastRect)
+		case ShowString:
+			showRun(o.Bytes)
+		case ShowArray:
+			var buf []byte
+			for _, e := range o.Elems {
+				if e.IsAdjust {
+					if math.Abs(e.Adjust) > tjSplitThreshold {
+						showRun(buf)
+						buf = nil
+					}
+					continue
+				}
+				buf = append(buf, e.Bytes...)
+			}
+			showRun(buf)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,111 @@
+package parser
+
+import (
+	"reflect"
+	"testing"
+)
+
+// recordingHandler embeds NopHandler and appends a short tag for each
+// callback it receives, so tests can assert on call order without
+// caring about callbacks they don't exercise.
+type recordingHandler struct {
+	NopHandler
+	events []string
+}
+
+func (r *recordingHandler) OnBeginText() { r.events = append(r.events, "BT") }
+func (r *recordingHandler) OnEndText()   { r.events = append(r.events, "ET") }
+func (r *recordingHandler) OnShowText(bytes []byte, tx, ty float64) {
+	r.events = append(r.events, "show:"+string(bytes))
+}
+func (r *recordingHandler) OnSetFont(name string, size float64) {
+	r.events = append(r.events, "font:"+name)
+}
+
+func TestWalkContentStream_ReportsOperatorsInOrder(t *testing.T) {
+	stream := []byte(`BT
+/F1 12 Tf
+1 0 0 1 0 0 Tm
+(hello)Tj
+ET`)
+
+	h := &recordingHandler{}
+	if err := WalkContentStream(stream, h); err != nil {
+		t.Fatalf("WalkContentStream: %v", err)
+	}
+
+	want := []string{"BT", "font:F1", "show:hello", "ET"}
+	if !reflect.DeepEqual(h.events, want) {
+		t.Errorf("events = %v, want %v", h.events, want)
+	}
+}
+
+func TestWalkContentStream_SplitsTJOnLargeAdjustment(t *testing.T) {
+	// -600 is past tjSplitThreshold, so it should split "left" and
+	// "right" into separate OnShowText calls; the later small kern
+	// should stay merged into "right".
+	stream := []byte(`BT
+[(left)-600(rig)-20(ht)]TJ
+ET`)
+
+	h := &recordingHandler{}
+	if err := WalkContentStream(stream, h); err != nil {
+		t.Fatalf("WalkContentStream: %v", err)
+	}
+
+	want := []string{"BT", "show:left", "show:right", "ET"}
+	if !reflect.DeepEqual(h.events, want) {
+		t.Errorf("events = %v, want %v", h.events, want)
+	}
+}
+
+func TestMultiHandler_FansOutToAllHandlers(t *testing.T) {
+	stream := []byte(`BT
+(hi)Tj
+ET`)
+
+	a, b := &recordingHandler{}, &recordingHandler{}
+	if err := WalkContentStream(stream, MultiHandler{a, b}); err != nil {
+		t.Fatalf("WalkContentStream: %v", err)
+	}
+
+	want := []string{"BT", "show:hi", "ET"}
+	if !reflect.DeepEqual(a.events, want) {
+		t.Errorf("handler a events = %v, want %v", a.events, want)
+	}
+	if !reflect.DeepEqual(b.events, want) {
+		t.Errorf("handler b events = %v, want %v", b.events, want)
+	}
+}
+
+func TestWalkContentStream_ReportsClipRect(t *testing.T) {
+	stream := []byte(`q
+10 20 100 50 re
+W
+n
+Q`)
+
+	rec := &clipRecorder{}
+	if err := WalkContentStream(stream, rec); err != nil {
+		t.Fatalf("WalkContentStream: %v", err)
+	}
+
+	if !rec.saw {
+		t.Fatal("expected OnClip to be called")
+	}
+	want := Rect{X: 10, Y: 20, W: 100, H: 50}
+	if rec.rect != want {
+		t.Errorf("clip rect = %+v, want %+v", rec.rect, want)
+	}
+}
+
+type clipRecorder struct {
+	NopHandler
+	rect Rect
+	saw  bool
+}
+
+func (c *clipRecorder) OnClip(rect Rect) {
+	c.rect = rect
+	c.saw = true
+}